@@ -0,0 +1,55 @@
+package go_srf
+
+import (
+	"github.com/klauspost/compress/zstd"
+	"math/rand"
+	"testing"
+)
+
+func randomPayload(size int) []byte {
+	buf := make([]byte, size)
+	rand.New(rand.NewSource(int64(size))).Read(buf)
+	return buf
+}
+
+// benchZstdPooled round-trips payload through the pooled Zstd codec
+func benchZstdPooled(b *testing.B, payload []byte) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		enc := Zstd.EncodeAll(payload, nil)
+		if _, err := Zstd.DecodeAll(enc, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchZstdUnpooled round-trips payload, constructing a fresh encoder/decoder
+// every time, matching the original per-record allocation behaviour
+func benchZstdUnpooled(b *testing.B, payload []byte) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(EncoderConcurrency), zstd.WithEncoderCRC(true))
+		if err != nil {
+			b.Fatal(err)
+		}
+		out := enc.EncodeAll(payload, nil)
+		_ = enc.Close()
+
+		dec, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(DecoderConcurrency))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := dec.DecodeAll(out, nil); err != nil {
+			b.Fatal(err)
+		}
+		dec.Close()
+	}
+}
+
+func BenchmarkZstdPooledSmall(b *testing.B)  { benchZstdPooled(b, randomPayload(256)) }
+func BenchmarkZstdPooledMedium(b *testing.B) { benchZstdPooled(b, randomPayload(16*1024)) }
+func BenchmarkZstdPooledLarge(b *testing.B)  { benchZstdPooled(b, randomPayload(1024*1024)) }
+
+func BenchmarkZstdUnpooledSmall(b *testing.B)  { benchZstdUnpooled(b, randomPayload(256)) }
+func BenchmarkZstdUnpooledMedium(b *testing.B) { benchZstdUnpooled(b, randomPayload(16*1024)) }
+func BenchmarkZstdUnpooledLarge(b *testing.B)  { benchZstdUnpooled(b, randomPayload(1024*1024)) }
@@ -4,7 +4,6 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"errors"
-	"github.com/klauspost/compress/zstd"
 	"io"
 	"reflect"
 )
@@ -39,8 +38,7 @@ type record struct {
 
 var (
 	// Read Errors
-	ErrInvalidHeader   = errors.New("invalid srf header")
-	ErrInvalidZeroBits = errors.New("garbage found in reserved bits")
+	ErrInvalidHeader = errors.New("invalid srf header")
 )
 
 func validateHeader(r io.Reader) error {
@@ -55,24 +53,35 @@ func validateHeader(r io.Reader) error {
 	return nil
 }
 
-func (r *record) read(src io.Reader) error {
+func (r *record) read(src io.Reader, forceVerify bool) error {
 	if err := validateHeader(src); err != nil {
 		return err
 	}
 
-	// process compression & type
+	// process codec, type & compression
 	var v uint32
 	if err := binary.Read(src, binary.LittleEndian, &v); err != nil {
 		return err
 	}
-	// check if reserved bits are zero
-	if ((v >> 16) & 0x7F) != 0 {
-		return ErrInvalidZeroBits
-	}
 
-	// extract type & compression
+	// extract type, codec, compression, dictionary & checksum presence
 	r.recordType = uint16(v & 0xFF)
+	codecID := uint8((v >> 16) & 0x7F)
 	compressed := (v>>31)&0x1 == 1
+	hasDict := v&dictFlagBit != 0
+	hasChecksum := v&checksumFlagBit != 0
+
+	var dictReader *DictReader
+	if hasDict {
+		var dictID uint32
+		if err := binary.Read(src, binary.LittleEndian, &dictID); err != nil {
+			return err
+		}
+		var err error
+		if dictReader, err = dictReaderByID(dictID); err != nil {
+			return err
+		}
+	}
 
 	var szMeta uint32
 	var szData uint64
@@ -83,14 +92,28 @@ func (r *record) read(src io.Reader) error {
 		return err
 	}
 
-	var d *zstd.Decoder
+	var codec Codec
 	var err error
-	if szMeta > 0 || compressed {
-		d, err = zstd.NewReader(src, zstd.WithDecoderConcurrency(DecoderConcurrency))
-		if err != nil {
+	if !hasDict && (szMeta > 0 || compressed) {
+		// records written before codec bits existed (identifiable by the
+		// absence of a checksum, a feature added after codec selection) used
+		// a zero codec id to mean zstd: via the compressed flag for the
+		// body, and unconditionally for metadata, since the original Write
+		// always zstd-compressed meta regardless of the body's compression
+		// flag; records from this series always carry a checksum, so a zero
+		// id there is trusted literally as CodecNone
+		if codecID == CodecNone && !hasChecksum {
+			codecID = CodecZstd
+		}
+		if codec, err = codecByID(codecID); err != nil {
 			return err
 		}
-		defer d.Close()
+	}
+	decode := func(buf []byte) ([]byte, error) {
+		if hasDict {
+			return dictReader.dec.DecodeAll(buf, nil)
+		}
+		return codec.DecodeAll(buf, nil)
 	}
 
 	// read meta
@@ -99,7 +122,7 @@ func (r *record) read(src io.Reader) error {
 		if _, err = io.ReadFull(src, buf); err != nil {
 			return err
 		}
-		if r.meta, err = d.DecodeAll(buf, nil); err != nil {
+		if r.meta, err = decode(buf); err != nil {
 			return err
 		}
 	}
@@ -110,7 +133,7 @@ func (r *record) read(src io.Reader) error {
 		if _, err := io.ReadFull(src, buf); err != nil {
 			return err
 		}
-		if r.body, err = d.DecodeAll(buf, nil); err != nil {
+		if r.body, err = decode(buf); err != nil {
 			return err
 		}
 	} else {
@@ -120,6 +143,19 @@ func (r *record) read(src io.Reader) error {
 		}
 	}
 
+	// read & verify trailing checksum, if present
+	if hasChecksum {
+		var sum uint64
+		if err := binary.Read(src, binary.LittleEndian, &sum); err != nil {
+			return err
+		}
+		if forceVerify || !SkipChecksumVerification {
+			if recordChecksum(r.recordType, r.meta, r.body) != sum {
+				return ErrChecksumMismatch
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -152,8 +188,14 @@ func (r *record) String() string {
 // Read reads a record from a io.Reader
 // if read is successful, returns a new record; if not, returns the error
 func Read(src io.Reader) (Record, error) {
+	return readRecord(src, false)
+}
+
+// readRecord reads a record from src; if forceVerify is true, its checksum is
+// verified regardless of SkipChecksumVerification
+func readRecord(src io.Reader, forceVerify bool) (Record, error) {
 	r := &record{}
-	if err := r.read(src); err != nil {
+	if err := r.read(src, forceVerify); err != nil {
 		return nil, err
 	}
 	return r, nil
@@ -177,60 +219,126 @@ func ReadAll(src io.Reader) ([]Record, error) {
 
 // Write writes a new record, field by field, to an io.Writer
 // - if the record has no metadata, meta value should be nil;
-// - if compress is true, data is compressed using Zstd before it is written;
-func Write(dst io.Writer, recordType uint16, data []byte, meta any, compress bool) error {
+// - codec selects the compression used for both metadata and body; pass None for no compression;
+func Write(dst io.Writer, recordType uint16, data []byte, meta any, codec Codec) error {
 	var buf []byte
 	var rawMeta []byte
 	var rawRecord []byte
-	var enc *zstd.Encoder
 	var err error
 	hasMeta := meta != nil && !reflect.ValueOf(meta).IsNil()
 
-	// initialize compressor if necessary
-	if hasMeta || compress {
-		enc, err = zstd.NewWriter(dst, zstd.WithEncoderConcurrency(EncoderConcurrency), zstd.WithEncoderCRC(true))
-		if err != nil {
-			return err
-		}
+	if codec == nil {
+		codec = None
 	}
+	compress := codec.ID() != CodecNone
 
 	if hasMeta {
 		if buf, err = json.Marshal(meta); err != nil {
 			return err
 		}
-		// compress meta
-		rawMeta = enc.EncodeAll(buf, nil)
+		rawMeta = codec.EncodeAll(buf, nil)
 	}
 
 	if compress {
-		rawRecord = enc.EncodeAll(data, nil)
+		rawRecord = codec.EncodeAll(data, nil)
 	} else {
 		rawRecord = data
 	}
 
-	return RawWrite(dst, recordType, rawMeta, rawRecord, compress)
+	checksum := recordChecksum(recordType, buf, data)
+	return RawWrite(dst, recordType, rawMeta, rawRecord, codec, &checksum, nil)
 }
 
 // WriteString helper Write() function to write a text record
-func WriteString(dst io.Writer, recordType uint16, data string, meta any, compress bool) error {
-	return Write(dst, recordType, []byte(data), meta, compress)
+func WriteString(dst io.Writer, recordType uint16, data string, meta any, codec Codec) error {
+	return Write(dst, recordType, []byte(data), meta, codec)
+}
+
+// WriteZstd is a back-compat wrapper matching the original Write() signature,
+// selecting between Zstd and None based on compress
+func WriteZstd(dst io.Writer, recordType uint16, data []byte, meta any, compress bool) error {
+	if compress {
+		return Write(dst, recordType, data, meta, Zstd)
+	}
+	return Write(dst, recordType, data, meta, None)
+}
+
+// WriteS2 is the S2 equivalent of WriteZstd
+func WriteS2(dst io.Writer, recordType uint16, data []byte, meta any, compress bool) error {
+	if compress {
+		return Write(dst, recordType, data, meta, S2)
+	}
+	return Write(dst, recordType, data, meta, None)
+}
+
+// WriteDict writes a new record, compressing meta & body against dict's trained
+// zstd dictionary; small, structurally similar records compress far better this
+// way than with a lone per-record zstd frame
+func WriteDict(dst io.Writer, recordType uint16, data []byte, meta any, dict *DictWriter) error {
+	var buf []byte
+	var rawMeta []byte
+	var err error
+	hasMeta := meta != nil && !reflect.ValueOf(meta).IsNil()
+
+	if hasMeta {
+		if buf, err = json.Marshal(meta); err != nil {
+			return err
+		}
+		rawMeta = dict.enc.EncodeAll(buf, nil)
+	}
+	rawRecord := dict.enc.EncodeAll(data, nil)
+
+	checksum := recordChecksum(recordType, buf, data)
+	return RawWrite(dst, recordType, rawMeta, rawRecord, Zstd, &checksum, &dict.id)
+}
+
+// WriteRecordDict is the Record-based equivalent of WriteDict
+func WriteRecordDict(dst io.Writer, r Record, dict *DictWriter) error {
+	meta := r.Meta()
+	hasMeta := meta != nil && len(meta) > 0
+
+	var rawMeta []byte
+	if hasMeta {
+		rawMeta = dict.enc.EncodeAll(meta, nil)
+	}
+
+	plainBody := r.Bytes()
+	rawRecord := dict.enc.EncodeAll(plainBody, nil)
+
+	checksum := recordChecksum(r.Type(), meta, plainBody)
+	return RawWrite(dst, r.Type(), rawMeta, rawRecord, Zstd, &checksum, &dict.id)
 }
 
 // RawWrite write low-level record to stream
-//   - this function only performs the low-level write operation; metadata should already be compressed, and rawRecord
-//  may already be the compressed data, if isCompressed is true;
+//   - this function only performs the low-level write operation; metadata should already be encoded, and rawRecord
+//  may already be the encoded data, if codec is not None;
+//   - checksum, if non-nil, is appended after the body and its presence flagged in the header; it must have been
+//  computed over the decoded (pre-encoding) type, meta and body;
+//   - dictID, if non-nil, is written right after the header word and flags the record as dictionary-compressed;
+//  rawMeta/rawRecord must already be encoded against that dictionary;
 //   - passing wrong parameters to the function will result in invalid records being written;
-func RawWrite(dst io.Writer, rType uint16, rawMeta []byte, rawRecord []byte, isCompressed bool) error {
+func RawWrite(dst io.Writer, rType uint16, rawMeta []byte, rawRecord []byte, codec Codec, checksum *uint64, dictID *uint32) error {
+	if codec == nil {
+		codec = None
+	}
+
 	// write header
 	if err := binary.Write(dst, binary.LittleEndian, []byte(Magic)); err != nil {
 		return err
 	}
 
-	// type & flags
+	// type, codec & flags
 	t := uint32(rType) & 0xFF
-	if isCompressed {
+	t |= uint32(codec.ID()&0x7F) << 16
+	if codec.ID() != CodecNone {
 		t = t | (0x01 << 31)
 	}
+	if checksum != nil {
+		t |= checksumFlagBit
+	}
+	if dictID != nil {
+		t |= dictFlagBit
+	}
 
 	var lenMeta uint32
 	if rawMeta != nil && len(rawMeta) > 0 {
@@ -245,6 +353,13 @@ func RawWrite(dst io.Writer, rType uint16, rawMeta []byte, rawRecord []byte, isC
 		return err
 	}
 
+	// write dictionary id
+	if dictID != nil {
+		if err := binary.Write(dst, binary.LittleEndian, *dictID); err != nil {
+			return err
+		}
+	}
+
 	// write meta size
 	if err := binary.Write(dst, binary.LittleEndian, lenMeta); err != nil {
 		return err
@@ -266,38 +381,42 @@ func RawWrite(dst io.Writer, rType uint16, rawMeta []byte, rawRecord []byte, isC
 	if _, err := dst.Write(rawRecord); err != nil {
 		return err
 	}
+
+	// write trailing checksum
+	if checksum != nil {
+		if err := binary.Write(dst, binary.LittleEndian, *checksum); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// WriteRecord writes a Record struct to a io.Writer
-func WriteRecord(dst io.Writer, r Record, compress bool) error {
+// WriteRecord writes a Record struct to a io.Writer, using codec to encode both
+// metadata and body; pass None for no compression
+func WriteRecord(dst io.Writer, r Record, codec Codec) error {
 	var rawMeta []byte
 	var rawRecord []byte
-	var enc *zstd.Encoder
-	var err error
+
+	if codec == nil {
+		codec = None
+	}
+	compress := codec.ID() != CodecNone
 
 	meta := r.Meta()
 	hasMeta := meta != nil && len(meta) > 0
 
-	// initialize compressor if necessary
-	if hasMeta || compress {
-		enc, err = zstd.NewWriter(dst, zstd.WithEncoderConcurrency(EncoderConcurrency), zstd.WithEncoderCRC(true))
-		if err != nil {
-			return err
-		}
-	}
-
 	if hasMeta {
-		// compress meta
-		rawMeta = enc.EncodeAll(meta, nil)
+		rawMeta = codec.EncodeAll(meta, nil)
 	}
 
-	rawRecord = r.Bytes()
+	plainBody := r.Bytes()
+	rawRecord = plainBody
 	if compress {
-		rawRecord = enc.EncodeAll(rawRecord, nil)
+		rawRecord = codec.EncodeAll(rawRecord, nil)
 	}
 
-	return RawWrite(dst, r.Type(), rawMeta, rawRecord, compress)
+	checksum := recordChecksum(r.Type(), meta, plainBody)
+	return RawWrite(dst, r.Type(), rawMeta, rawRecord, codec, &checksum, nil)
 }
 
 // UnpackMeta extract JSON metadata into v if metadata exists
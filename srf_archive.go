@@ -0,0 +1,115 @@
+package go_srf
+
+import (
+	"bytes"
+	"context"
+	"sync"
+)
+
+// archiveIndex caches a parsed footer index for one object, keyed by name, so
+// repeated Extract calls don't re-fetch and re-parse it
+type archiveIndex struct {
+	offsets []int64
+	size    int64
+}
+
+// Archive composes a Storage backend with the footer index (see OpenIndexed),
+// so callers can extract records from disk or an S3-compatible store without
+// managing file handles or byte ranges themselves
+type Archive struct {
+	storage Storage
+
+	mu    sync.Mutex
+	cache map[string]*archiveIndex
+}
+
+// NewArchive returns an Archive backed by storage
+func NewArchive(storage Storage) *Archive {
+	return &Archive{storage: storage, cache: map[string]*archiveIndex{}}
+}
+
+// footerTailSize bounds how many trailing bytes are fetched to locate and parse
+// an object's footer index; large enough for tens of thousands of records
+const footerTailSize = 1 << 20
+
+// indexFor returns the cached footer offsets for name, fetching and parsing
+// them on first use
+func (a *Archive) indexFor(ctx context.Context, name string) (*archiveIndex, error) {
+	a.mu.Lock()
+	if idx, ok := a.cache[name]; ok {
+		a.mu.Unlock()
+		return idx, nil
+	}
+	a.mu.Unlock()
+
+	info, err := a.storage.Stat(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	tailLen := int64(footerTailSize)
+	if tailLen > info.Size {
+		tailLen = info.Size
+	}
+	rc, err := a.storage.Range(ctx, name, info.Size-tailLen, tailLen)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(rc); err != nil {
+		return nil, err
+	}
+
+	offsets, err := readFooter(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+
+	idx := &archiveIndex{offsets: offsets, size: info.Size}
+	a.mu.Lock()
+	a.cache[name] = idx
+	a.mu.Unlock()
+	return idx, nil
+}
+
+// Extract reads count records from name starting at start, using the object's
+// footer index to issue a single Range request covering exactly the records
+// needed, rather than transferring the whole object
+func (a *Archive) Extract(ctx context.Context, name string, start int64, count int64) ([]Record, error) {
+	if start < 0 {
+		return nil, ErrInvalidStartOffset
+	}
+	if count < 1 {
+		return nil, ErrInvalidCount
+	}
+
+	idx, err := a.indexFor(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if start >= int64(len(idx.offsets)) {
+		return nil, ErrInvalidStartOffset
+	}
+
+	// clamp count to the records actually remaining so the fetched range never
+	// reaches into the trailing footer block
+	if remaining := int64(len(idx.offsets)) - start; count > remaining {
+		count = remaining
+	}
+
+	// the range ends where the record after the last one needed starts, or at
+	// the footer block ([N][offsets...][len][magic]) for the last record
+	end := idx.size - 12 - int64(8+8*len(idx.offsets))
+	if start+count < int64(len(idx.offsets)) {
+		end = idx.offsets[start+count]
+	}
+	rc, err := a.storage.Range(ctx, name, idx.offsets[start], end-idx.offsets[start])
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return Extract(rc, 0, count, true)
+}
@@ -0,0 +1,31 @@
+package go_srf
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/cespare/xxhash/v2"
+)
+
+// checksumFlagBit marks the presence of a trailing xxhash64 checksum; bit 23
+// sits between the codec id (bits 16-22) and the compressed flag (bit 31)
+const checksumFlagBit = uint32(1) << 23
+
+// ErrChecksumMismatch is returned by Read when a record's trailing checksum
+// does not match its decoded type, meta and body
+var ErrChecksumMismatch = errors.New("srf record checksum mismatch")
+
+// SkipChecksumVerification disables checksum verification during Read, for callers
+// that trust their storage and want to avoid the extra hashing pass; Verify always
+// checks checksums regardless of this flag
+var SkipChecksumVerification = false
+
+// recordChecksum computes the xxhash64 of a record's decoded type, meta and body
+func recordChecksum(rType uint16, meta []byte, body []byte) uint64 {
+	h := xxhash.New()
+	var typeBuf [2]byte
+	binary.LittleEndian.PutUint16(typeBuf[:], rType)
+	_, _ = h.Write(typeBuf[:])
+	_, _ = h.Write(meta)
+	_, _ = h.Write(body)
+	return h.Sum64()
+}
@@ -0,0 +1,59 @@
+package go_srf
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestChecksumRoundTrip(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := Write(buf, TypeText, []byte("hello world"), nil, Zstd)
+	assert.NoError(t, err)
+
+	r, err := Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello world"), r.Bytes())
+}
+
+func TestChecksumMismatch(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := Write(buf, TypeText, []byte("hello world"), nil, None)
+	assert.NoError(t, err)
+
+	raw := buf.Bytes()
+	// flip the last body byte, which sits just before the trailing 8-byte checksum
+	raw[len(raw)-1-8] ^= 0xFF
+
+	_, err = Read(bytes.NewReader(raw))
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+}
+
+func TestSkipChecksumVerification(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := Write(buf, TypeText, []byte("hello world"), nil, None)
+	assert.NoError(t, err)
+
+	raw := buf.Bytes()
+	raw[len(raw)-1-8] ^= 0xFF
+
+	SkipChecksumVerification = true
+	defer func() { SkipChecksumVerification = false }()
+
+	r, err := Read(bytes.NewReader(raw))
+	assert.NoError(t, err)
+	assert.NotEqual(t, []byte("hello world"), r.Bytes())
+}
+
+func TestVerify(t *testing.T) {
+	buf := new(bytes.Buffer)
+	assert.NoError(t, Write(buf, TypeText, []byte("one"), nil, None))
+	assert.NoError(t, Write(buf, TypeText, []byte("two"), nil, Zstd))
+
+	SkipChecksumVerification = true
+	defer func() { SkipChecksumVerification = false }()
+
+	total, err := Verify(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+}
@@ -0,0 +1,132 @@
+package go_srf
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"github.com/klauspost/compress/s2"
+	"io"
+)
+
+// Codec identifiers, stored in bits 16-22 of the record header
+const (
+	CodecNone    uint8 = 0
+	CodecZstd    uint8 = 1
+	CodecS2      uint8 = 2
+	CodecDeflate uint8 = 3
+)
+
+// ErrUnknownCodec is returned when a record header references a codec id this
+// build does not know how to decode
+var ErrUnknownCodec = errors.New("unknown srf codec")
+
+// Codec encodes and decodes record payloads (meta and/or body); implementations
+// are registered in codecs and selected by the id stored in the record header
+type Codec interface {
+	// ID returns the codec identifier stored in the record header
+	ID() uint8
+	// EncodeAll appends the encoded form of src to dst and returns the result
+	EncodeAll(src, dst []byte) []byte
+	// DecodeAll appends the decoded form of src to dst and returns the result
+	DecodeAll(src, dst []byte) ([]byte, error)
+}
+
+var (
+	// None is the no-op codec; EncodeAll/DecodeAll pass data through unchanged
+	None Codec = noneCodec{}
+	// Zstd matches the original hardcoded compression behaviour
+	Zstd Codec = zstdCodec{}
+	// S2 trades compression ratio for speed, using github.com/klauspost/compress/s2
+	S2 Codec = s2Codec{}
+	// Deflate uses the standard library's compress/flate
+	Deflate Codec = deflateCodec{}
+)
+
+// codecs maps codec ids to their implementation
+var codecs = map[uint8]Codec{
+	CodecNone:    None,
+	CodecZstd:    Zstd,
+	CodecS2:      S2,
+	CodecDeflate: Deflate,
+}
+
+// codecByID looks up a registered codec, returning ErrUnknownCodec if none matches
+func codecByID(id uint8) (Codec, error) {
+	c, ok := codecs[id]
+	if !ok {
+		return nil, ErrUnknownCodec
+	}
+	return c, nil
+}
+
+type noneCodec struct{}
+
+func (noneCodec) ID() uint8 { return CodecNone }
+
+func (noneCodec) EncodeAll(src, dst []byte) []byte {
+	return append(dst, src...)
+}
+
+func (noneCodec) DecodeAll(src, dst []byte) ([]byte, error) {
+	return append(dst, src...), nil
+}
+
+type zstdCodec struct{}
+
+func (zstdCodec) ID() uint8 { return CodecZstd }
+
+func (zstdCodec) EncodeAll(src, dst []byte) []byte {
+	enc := getZstdEncoder()
+	defer putZstdEncoder(enc)
+	return enc.EncodeAll(src, dst)
+}
+
+func (zstdCodec) DecodeAll(src, dst []byte) ([]byte, error) {
+	dec := getZstdDecoder()
+	defer putZstdDecoder(dec)
+	return dec.DecodeAll(src, dst)
+}
+
+type s2Codec struct{}
+
+func (s2Codec) ID() uint8 { return CodecS2 }
+
+func (s2Codec) EncodeAll(src, dst []byte) []byte {
+	buf := make([]byte, s2.MaxEncodedLen(len(src)))
+	return append(dst, s2.Encode(buf, src)...)
+}
+
+func (s2Codec) DecodeAll(src, dst []byte) ([]byte, error) {
+	n, err := s2.DecodedLen(src)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	out, err := s2.Decode(buf, src)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, out...), nil
+}
+
+type deflateCodec struct{}
+
+func (deflateCodec) ID() uint8 { return CodecDeflate }
+
+func (deflateCodec) EncodeAll(src, dst []byte) []byte {
+	buf := new(bytes.Buffer)
+	w, _ := flate.NewWriter(buf, flate.DefaultCompression)
+	_, _ = w.Write(src)
+	_ = w.Close()
+	return append(dst, buf.Bytes()...)
+}
+
+func (deflateCodec) DecodeAll(src, dst []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(src))
+	defer r.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, out...), nil
+}
@@ -0,0 +1,79 @@
+package go_srf
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testWriteReadCodec(t *testing.T, codec Codec) {
+	buf := new(bytes.Buffer)
+	meta := map[string]int{"k": 1}
+	data := []byte("the quick brown fox jumps over the lazy dog")
+
+	err := Write(buf, TypeText, data, meta, codec)
+	assert.NoError(t, err)
+
+	r, err := Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, data, r.Bytes())
+	assert.True(t, r.HasMeta())
+
+	var gotMeta map[string]int
+	err = UnpackMeta(r, &gotMeta)
+	assert.NoError(t, err)
+	assert.Equal(t, meta, gotMeta)
+}
+
+func TestCodecs(t *testing.T) {
+	testWriteReadCodec(t, None)
+	testWriteReadCodec(t, Zstd)
+	testWriteReadCodec(t, S2)
+	testWriteReadCodec(t, Deflate)
+}
+
+func TestWriteZstdWriteS2BackCompat(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := WriteZstd(buf, TypeBinary, []byte("payload"), nil, true)
+	assert.NoError(t, err)
+	r, err := Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("payload"), r.Bytes())
+
+	buf = new(bytes.Buffer)
+	err = WriteS2(buf, TypeBinary, []byte("payload"), nil, true)
+	assert.NoError(t, err)
+	r, err = Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("payload"), r.Bytes())
+}
+
+// TestLegacyZstdMeta simulates a pre-codec-bits record: no checksum, an
+// uncompressed body, and metadata that was always zstd-compressed by the
+// original Write, with the (now repurposed) codec id bits left at zero
+func TestLegacyZstdMeta(t *testing.T) {
+	rawMeta := Zstd.EncodeAll([]byte(`{"k":1}`), nil)
+	body := []byte("payload")
+
+	buf := new(bytes.Buffer)
+	err := RawWrite(buf, TypeJSON, rawMeta, body, None, nil, nil)
+	assert.NoError(t, err)
+
+	r, err := Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, body, r.Bytes())
+	assert.Equal(t, []byte(`{"k":1}`), r.Meta())
+}
+
+func TestUnknownCodec(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := Write(buf, TypeBinary, []byte("payload"), nil, Zstd)
+	assert.NoError(t, err)
+
+	// corrupt the codec id bits (16-22), which live in the third header byte,
+	// to a value with no registered codec
+	raw := buf.Bytes()
+	raw[6] = 0x7F
+	_, err = Read(bytes.NewReader(raw))
+	assert.ErrorIs(t, err, ErrUnknownCodec)
+}
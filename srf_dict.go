@@ -0,0 +1,141 @@
+package go_srf
+
+import (
+	"errors"
+	"github.com/klauspost/compress/zstd"
+	"sort"
+	"sync"
+)
+
+// dictFlagBit marks the presence of a 4-byte dictionary id, written immediately
+// after the type/codec/flags word; bit 24 sits just above the codec id (16-22)
+// and the checksum flag (23)
+const dictFlagBit = uint32(1) << 24
+
+// ErrNoDictionary is returned when a record references a dictionary id that
+// hasn't been registered with RegisterDictReader in this process
+var ErrNoDictionary = errors.New("no dictionary registered for id")
+
+// DictWriter compresses record meta/body against a trained zstd dictionary,
+// which improves ratio dramatically on small, structurally similar records
+// where a lone per-record zstd frame has no history to draw on
+type DictWriter struct {
+	id  uint32
+	enc *zstd.Encoder
+}
+
+// NewDictWriter builds a DictWriter from raw dictionary content (e.g. produced
+// by TrainDictionary) tagged with id; id is written into every record's header
+// so readers know which dictionary to decode against
+func NewDictWriter(id uint32, dict []byte) (*DictWriter, error) {
+	enc, err := zstd.NewWriter(nil,
+		zstd.WithEncoderConcurrency(EncoderConcurrency),
+		zstd.WithEncoderCRC(true),
+		zstd.WithEncoderDictRaw(id, dict),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &DictWriter{id: id, enc: enc}, nil
+}
+
+// Close releases the writer's underlying zstd encoder
+func (w *DictWriter) Close() error {
+	return w.enc.Close()
+}
+
+// DictReader decodes records compressed against a trained zstd dictionary
+type DictReader struct {
+	id  uint32
+	dec *zstd.Decoder
+}
+
+// NewDictReader builds a DictReader for dictionary id; register it with
+// RegisterDictReader so Read() can resolve it automatically
+func NewDictReader(id uint32, dict []byte) (*DictReader, error) {
+	dec, err := zstd.NewReader(nil,
+		zstd.WithDecoderConcurrency(DecoderConcurrency),
+		zstd.WithDecoderDictRaw(id, dict),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &DictReader{id: id, dec: dec}, nil
+}
+
+var (
+	dictReadersMu sync.RWMutex
+	dictReaders   = map[uint32]*DictReader{}
+)
+
+// RegisterDictReader makes r available to Read() for any record whose header
+// carries r's dictionary id
+func RegisterDictReader(r *DictReader) {
+	dictReadersMu.Lock()
+	dictReaders[r.id] = r
+	dictReadersMu.Unlock()
+}
+
+// dictReaderByID looks up a registered DictReader, returning ErrNoDictionary if none matches
+func dictReaderByID(id uint32) (*DictReader, error) {
+	dictReadersMu.RLock()
+	r, ok := dictReaders[id]
+	dictReadersMu.RUnlock()
+	if !ok {
+		return nil, ErrNoDictionary
+	}
+	return r, nil
+}
+
+// TrainDictionary builds a raw content dictionary from samples, for use with
+// NewDictWriter/NewDictReader; this is a naive reference trainer - it ranks
+// samples by how much they overlap with the rest of the corpus and concatenates
+// the most representative ones until size is reached. For large or
+// production corpora, prefer training with the upstream zstd CLI
+// (zstd --train) and loading the resulting dictionary file's bytes directly.
+func TrainDictionary(samples [][]byte, size int) ([]byte, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("no samples provided")
+	}
+	if size <= 0 {
+		return nil, errors.New("size must be positive")
+	}
+
+	type scored struct {
+		data  []byte
+		score int
+	}
+	ranked := make([]scored, len(samples))
+	for i, s := range samples {
+		ranked[i] = scored{data: s, score: sharedPrefixScore(s, samples)}
+	}
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+
+	dict := make([]byte, 0, size)
+	for _, s := range ranked {
+		if len(dict) >= size {
+			break
+		}
+		remaining := size - len(dict)
+		if remaining < len(s.data) {
+			dict = append(dict, s.data[:remaining]...)
+		} else {
+			dict = append(dict, s.data...)
+		}
+	}
+	return dict, nil
+}
+
+// sharedPrefixScore sums how many leading bytes sample shares with every sample
+// in the corpus (itself included), as a cheap proxy for "how representative is this sample"
+func sharedPrefixScore(sample []byte, corpus [][]byte) int {
+	total := 0
+	for _, other := range corpus {
+		n := 0
+		for n < len(sample) && n < len(other) && sample[n] == other[n] {
+			n++
+		}
+		total += n
+	}
+	return total
+}
@@ -0,0 +1,82 @@
+package go_srf
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func smallJSONCorpus(n int) [][]byte {
+	samples := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		samples[i] = []byte(fmt.Sprintf(
+			`{"id":%d,"name":"user-%d","email":"user%d@example.com","active":true,"role":"member"}`,
+			i, i, i))
+	}
+	return samples
+}
+
+func TestTrainDictionaryImprovesSmallRecordCompression(t *testing.T) {
+	samples := smallJSONCorpus(50)
+
+	dict, err := TrainDictionary(samples, 4096)
+	assert.NoError(t, err)
+
+	dw, err := NewDictWriter(1, dict)
+	assert.NoError(t, err)
+	defer dw.Close()
+
+	var plainTotal, dictTotal int
+	for _, s := range samples {
+		plainTotal += len(Zstd.EncodeAll(s, nil))
+		dictTotal += len(dw.enc.EncodeAll(s, nil))
+	}
+
+	ratio := float64(plainTotal) / float64(dictTotal)
+	assert.Greater(t, ratio, 2.0, "expected >2x improvement, got plain=%d dict=%d (%.2fx)", plainTotal, dictTotal, ratio)
+}
+
+func TestDictWriteRead(t *testing.T) {
+	samples := smallJSONCorpus(50)
+	dict, err := TrainDictionary(samples, 4096)
+	assert.NoError(t, err)
+
+	dw, err := NewDictWriter(2, dict)
+	assert.NoError(t, err)
+	defer dw.Close()
+
+	dr, err := NewDictReader(2, dict)
+	assert.NoError(t, err)
+	RegisterDictReader(dr)
+
+	buf := new(bytes.Buffer)
+	err = WriteDict(buf, TypeJSON, samples[0], map[string]string{"k": "v"}, dw)
+	assert.NoError(t, err)
+
+	r, err := Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, samples[0], r.Bytes())
+
+	var meta map[string]string
+	err = UnpackMeta(r, &meta)
+	assert.NoError(t, err)
+	assert.Equal(t, "v", meta["k"])
+}
+
+func TestDictUnregisteredID(t *testing.T) {
+	samples := smallJSONCorpus(10)
+	dict, err := TrainDictionary(samples, 1024)
+	assert.NoError(t, err)
+
+	dw, err := NewDictWriter(999, dict)
+	assert.NoError(t, err)
+	defer dw.Close()
+
+	buf := new(bytes.Buffer)
+	err = WriteDict(buf, TypeJSON, samples[0], nil, dw)
+	assert.NoError(t, err)
+
+	_, err = Read(buf)
+	assert.ErrorIs(t, err, ErrNoDictionary)
+}
@@ -0,0 +1,204 @@
+package go_srf
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+const (
+	// IndexMagic identifies an SRF footer index block
+	IndexMagic = "SRFI"
+)
+
+var (
+	// ErrNoIndex is returned when a stream has no (valid) footer index
+	ErrNoIndex = errors.New("no srf footer index found")
+)
+
+// IndexedReader provides O(1) random access to records in an io.ReadSeeker
+// backed SRF stream, using a footer index read once at Open time
+type IndexedReader struct {
+	r       io.ReadSeeker
+	offsets []int64
+}
+
+// OpenIndexed reads the footer index from r and returns an IndexedReader
+// ready for random access; r must not be positioned mid-stream
+func OpenIndexed(r io.ReadSeeker) (*IndexedReader, error) {
+	offsets, err := readFooter(r)
+	if err != nil {
+		return nil, err
+	}
+	return &IndexedReader{r: r, offsets: offsets}, nil
+}
+
+// Count returns the number of records covered by the index
+func (ir *IndexedReader) Count() int64 {
+	return int64(len(ir.offsets))
+}
+
+// ReadAt seeks directly to record n and reads it; n starts at 0
+func (ir *IndexedReader) ReadAt(n int64) (Record, error) {
+	if n < 0 || n >= int64(len(ir.offsets)) {
+		return nil, ErrInvalidStartOffset
+	}
+	if _, err := ir.r.Seek(ir.offsets[n], io.SeekStart); err != nil {
+		return nil, err
+	}
+	return Read(ir.r)
+}
+
+// ReadRange seeks to start and reads up to count records, stopping early
+// at EOF if allowPrematureEnd is true; start starts at 0
+func (ir *IndexedReader) ReadRange(start int64, count int64, allowPrematureEnd bool) ([]Record, error) {
+	if start < 0 {
+		return nil, ErrInvalidStartOffset
+	}
+	if count < 1 {
+		return nil, ErrInvalidCount
+	}
+	if start >= int64(len(ir.offsets)) {
+		if allowPrematureEnd {
+			return make([]Record, 0), nil
+		}
+		return nil, io.EOF
+	}
+	if _, err := ir.r.Seek(ir.offsets[start], io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	result := make([]Record, 0, count)
+	for i := start; i < int64(len(ir.offsets)) && count > 0; i++ {
+		r, err := Read(ir.r)
+		if err != nil {
+			if errors.Is(err, io.EOF) && allowPrematureEnd {
+				return result, nil
+			}
+			return nil, err
+		}
+		result = append(result, r)
+		count--
+	}
+	if count > 0 && !allowPrematureEnd {
+		return nil, io.EOF
+	}
+	return result, nil
+}
+
+// BuildIndex walks every record in src from its current position, recording
+// the start offset of each; it can be used to retrofit an index over an
+// existing stream that was written without one
+func BuildIndex(src io.ReadSeeker) ([]int64, error) {
+	var offsets []int64
+	for {
+		offset, err := src.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		if err := skipRead(src); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		offsets = append(offsets, offset)
+	}
+	return offsets, nil
+}
+
+// WriteIndexed writes records to dst followed by a footer index, allowing
+// the result to later be opened with OpenIndexed for random access
+func WriteIndexed(dst io.WriteSeeker, records []Record, compress bool) error {
+	codec := None
+	if compress {
+		codec = Zstd
+	}
+
+	offsets := make([]int64, 0, len(records))
+	for _, r := range records {
+		offset, err := dst.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		offsets = append(offsets, offset)
+		if err := WriteRecord(dst, r, codec); err != nil {
+			return err
+		}
+	}
+	return writeFooter(dst, offsets)
+}
+
+// writeFooter appends a footer index block: [N][offset_0]...[offset_N-1][len][magic]
+func writeFooter(dst io.Writer, offsets []int64) error {
+	n := int64(len(offsets))
+	if err := binary.Write(dst, binary.LittleEndian, n); err != nil {
+		return err
+	}
+	for _, o := range offsets {
+		if err := binary.Write(dst, binary.LittleEndian, o); err != nil {
+			return err
+		}
+	}
+
+	// length of the footer data (N + offsets), not counting the trailing
+	// length field and magic themselves
+	length := uint64(8 + 8*len(offsets))
+	if err := binary.Write(dst, binary.LittleEndian, length); err != nil {
+		return err
+	}
+	if err := binary.Write(dst, binary.LittleEndian, []byte(IndexMagic)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readFooter locates and parses the trailing footer index of r, if present
+func readFooter(r io.ReadSeeker) ([]int64, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if end < 12 {
+		return nil, ErrNoIndex
+	}
+
+	if _, err := r.Seek(end-12, io.SeekStart); err != nil {
+		return nil, err
+	}
+	var length uint64
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return nil, err
+	}
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return nil, err
+	}
+	if string(magic) != IndexMagic {
+		return nil, ErrNoIndex
+	}
+
+	footerStart := end - 12 - int64(length)
+	if footerStart < 0 {
+		return nil, ErrNoIndex
+	}
+	if _, err := r.Seek(footerStart, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var n int64
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return nil, err
+	}
+	if n < 0 || uint64(8+8*n) != length {
+		return nil, ErrNoIndex
+	}
+
+	offsets := make([]int64, n)
+	for i := int64(0); i < n; i++ {
+		if err := binary.Read(r, binary.LittleEndian, &offsets[i]); err != nil {
+			return nil, err
+		}
+	}
+	return offsets, nil
+}
@@ -0,0 +1,126 @@
+package go_srf
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+// seekBuffer is a minimal in-memory io.ReadWriteSeeker for exercising the
+// indexed reader/writer against something other than a file or network stream
+type seekBuffer struct {
+	buf []byte
+	pos int64
+}
+
+func (s *seekBuffer) Write(p []byte) (int, error) {
+	if s.pos < int64(len(s.buf)) {
+		n := copy(s.buf[s.pos:], p)
+		if n < len(p) {
+			s.buf = append(s.buf, p[n:]...)
+		}
+		s.pos += int64(len(p))
+		return len(p), nil
+	}
+	s.buf = append(s.buf, p...)
+	s.pos += int64(len(p))
+	return len(p), nil
+}
+
+func (s *seekBuffer) Read(p []byte) (int, error) {
+	if s.pos >= int64(len(s.buf)) {
+		return 0, io.EOF
+	}
+	n := copy(p, s.buf[s.pos:])
+	s.pos += int64(n)
+	return n, nil
+}
+
+func (s *seekBuffer) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = s.pos + offset
+	case io.SeekEnd:
+		abs = int64(len(s.buf)) + offset
+	}
+	s.pos = abs
+	return abs, nil
+}
+
+func indexedRecords() []Record {
+	return []Record{
+		&record{recordType: TypeText, body: []byte("alpha")},
+		&record{recordType: TypeText, body: []byte("beta")},
+		&record{recordType: TypeJSON, meta: []byte(`{"k":1}`), body: []byte(`{"v":2}`)},
+	}
+}
+
+func TestWriteIndexedAndOpenIndexed(t *testing.T) {
+	sb := &seekBuffer{}
+	recs := indexedRecords()
+
+	err := WriteIndexed(sb, recs, false)
+	assert.NoError(t, err)
+
+	ir, err := OpenIndexed(sb)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(recs)), ir.Count())
+
+	r, err := ir.ReadAt(2)
+	assert.NoError(t, err)
+	assert.Equal(t, recs[2].Bytes(), r.Bytes())
+
+	rows, err := ir.ReadRange(1, 2, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, recs[1].Bytes(), rows[0].Bytes())
+	assert.Equal(t, recs[2].Bytes(), rows[1].Bytes())
+}
+
+func TestOpenIndexedNoFooter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := Write(buf, TypeText, []byte("plain"), nil, None)
+	assert.NoError(t, err)
+
+	sb := &seekBuffer{buf: buf.Bytes()}
+	_, err = OpenIndexed(sb)
+	assert.ErrorIs(t, err, ErrNoIndex)
+}
+
+func TestBuildIndex(t *testing.T) {
+	sb := &seekBuffer{}
+	recs := indexedRecords()
+	for _, r := range recs {
+		err := WriteRecord(sb, r, None)
+		assert.NoError(t, err)
+	}
+
+	_, err := sb.Seek(0, io.SeekStart)
+	assert.NoError(t, err)
+	offsets, err := BuildIndex(sb)
+	assert.NoError(t, err)
+	assert.Equal(t, len(recs), len(offsets))
+	assert.Equal(t, int64(0), offsets[0])
+
+	_, err = sb.Seek(offsets[1], io.SeekStart)
+	assert.NoError(t, err)
+	r, err := Read(sb)
+	assert.NoError(t, err)
+	assert.Equal(t, recs[1].Bytes(), r.Bytes())
+}
+
+func TestExtractSeeksWithIndex(t *testing.T) {
+	sb := &seekBuffer{}
+	recs := indexedRecords()
+	err := WriteIndexed(sb, recs, false)
+	assert.NoError(t, err)
+
+	rows, err := Extract(sb, 1, 1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, recs[1].Bytes(), rows[0].Bytes())
+}
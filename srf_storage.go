@@ -0,0 +1,114 @@
+package go_srf
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrInvalidName is returned when a Storage name would resolve outside the
+// backend's configured root (e.g. via ".." traversal or an absolute path)
+var ErrInvalidName = errors.New("srf: invalid storage name")
+
+// Info describes a named object in a Storage backend
+type Info struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Storage abstracts the src/dst of SRF streams over local disk, object stores,
+// or any other byte-addressable backend, so callers no longer have to arrange
+// their own file/S3 plumbing to use Extract/Copy/Read/Write
+type Storage interface {
+	// Open returns a seekable reader for name, e.g. for use with Extract/Copy/ReadAll
+	Open(ctx context.Context, name string) (io.ReadSeekCloser, error)
+	// Create returns a writer for a new object named name; it must fail if name already exists
+	Create(ctx context.Context, name string) (io.WriteCloser, error)
+	// Stat returns metadata about name
+	Stat(ctx context.Context, name string) (Info, error)
+	// Range returns a reader for the byte range [offset, offset+length) of name
+	Range(ctx context.Context, name string, offset int64, length int64) (io.ReadCloser, error)
+}
+
+// LocalStorage implements Storage over a directory on local disk
+type LocalStorage struct {
+	root string
+}
+
+// NewLocalStorage returns a Storage rooted at dir
+func NewLocalStorage(dir string) *LocalStorage {
+	return &LocalStorage{root: dir}
+}
+
+// path resolves name against the storage root, rejecting any name that would
+// escape it (absolute paths, "..", or symlink-free traversal via Clean)
+func (s *LocalStorage) path(name string) (string, error) {
+	if filepath.IsAbs(name) {
+		return "", ErrInvalidName
+	}
+	joined := filepath.Join(s.root, name)
+	root := filepath.Clean(s.root)
+	if joined != root && !strings.HasPrefix(joined, root+string(filepath.Separator)) {
+		return "", ErrInvalidName
+	}
+	return joined, nil
+}
+
+func (s *LocalStorage) Open(_ context.Context, name string) (io.ReadSeekCloser, error) {
+	p, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+// Create opens name for exclusive creation, refusing to overwrite an existing file
+func (s *LocalStorage) Create(_ context.Context, name string) (io.WriteCloser, error) {
+	p, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+}
+
+func (s *LocalStorage) Stat(_ context.Context, name string) (Info, error) {
+	p, err := s.path(name)
+	if err != nil {
+		return Info{}, err
+	}
+	fi, err := os.Stat(p)
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{Name: name, Size: fi.Size(), ModTime: fi.ModTime()}, nil
+}
+
+func (s *LocalStorage) Range(_ context.Context, name string, offset int64, length int64) (io.ReadCloser, error) {
+	p, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &limitedReadCloser{r: io.LimitReader(f, length), c: f}, nil
+}
+
+// limitedReadCloser bounds reads to a byte range while closing the underlying file
+type limitedReadCloser struct {
+	r io.Reader
+	c io.Closer
+}
+
+func (l *limitedReadCloser) Read(p []byte) (int, error) { return l.r.Read(p) }
+func (l *limitedReadCloser) Close() error               { return l.c.Close() }
@@ -0,0 +1,68 @@
+package go_srf
+
+import (
+	"context"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// S3Storage implements Storage over an S3-compatible object store via minio-go;
+// callers own the client's configuration (endpoint, credentials, TLS, etc.)
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Storage returns a Storage backed by bucket on client
+func NewS3Storage(client *minio.Client, bucket string) *S3Storage {
+	return &S3Storage{client: client, bucket: bucket}
+}
+
+func (s *S3Storage) Open(ctx context.Context, name string) (io.ReadSeekCloser, error) {
+	return s.client.GetObject(ctx, s.bucket, name, minio.GetObjectOptions{})
+}
+
+// Create streams written bytes to the object store; the upload completes, and
+// any error surfaces, on Close; unlike LocalStorage.Create, it does not check
+// whether name already exists, and an existing object is replaced
+func (s *S3Storage) Create(ctx context.Context, name string) (io.WriteCloser, error) {
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.client.PutObject(ctx, s.bucket, name, pr, -1, minio.PutObjectOptions{})
+		_ = pr.CloseWithError(err)
+		done <- err
+	}()
+	return &s3Writer{pw: pw, done: done}, nil
+}
+
+type s3Writer struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) { return w.pw.Write(p) }
+
+func (w *s3Writer) Close() error {
+	if err := w.pw.Close(); err != nil {
+		return err
+	}
+	return <-w.done
+}
+
+func (s *S3Storage) Stat(ctx context.Context, name string) (Info, error) {
+	oi, err := s.client.StatObject(ctx, s.bucket, name, minio.StatObjectOptions{})
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{Name: name, Size: oi.Size, ModTime: oi.LastModified}, nil
+}
+
+func (s *S3Storage) Range(ctx context.Context, name string, offset int64, length int64) (io.ReadCloser, error) {
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(offset, offset+length-1); err != nil {
+		return nil, err
+	}
+	return s.client.GetObject(ctx, s.bucket, name, opts)
+}
@@ -0,0 +1,100 @@
+package go_srf
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStorageArchiveExtract(t *testing.T) {
+	dir := t.TempDir()
+	f, err := os.Create(filepath.Join(dir, "test.srf"))
+	assert.NoError(t, err)
+	recs := indexedRecords()
+	assert.NoError(t, WriteIndexed(f, recs, false))
+	assert.NoError(t, f.Close())
+
+	storage := NewLocalStorage(dir)
+	arc := NewArchive(storage)
+
+	rows, err := arc.Extract(context.Background(), "test.srf", 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, recs[1].Bytes(), rows[0].Bytes())
+	assert.Equal(t, recs[2].Bytes(), rows[1].Bytes())
+}
+
+func TestLocalStorageArchiveExtractPrematureEnd(t *testing.T) {
+	dir := t.TempDir()
+	f, err := os.Create(filepath.Join(dir, "test.srf"))
+	assert.NoError(t, err)
+	recs := indexedRecords()
+	assert.NoError(t, WriteIndexed(f, recs, false))
+	assert.NoError(t, f.Close())
+
+	storage := NewLocalStorage(dir)
+	arc := NewArchive(storage)
+
+	rows, err := arc.Extract(context.Background(), "test.srf", 1, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, recs[1].Bytes(), rows[0].Bytes())
+	assert.Equal(t, recs[2].Bytes(), rows[1].Bytes())
+}
+
+func TestLocalStorageCreateRefusesOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	storage := NewLocalStorage(dir)
+
+	w, err := storage.Create(ctx, "once.srf")
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	_, err = storage.Create(ctx, "once.srf")
+	assert.Error(t, err)
+	assert.True(t, os.IsExist(err))
+}
+
+func TestLocalStorageRejectsTraversal(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(filepath.Dir(dir), "secret.txt")
+	assert.NoError(t, os.WriteFile(secret, []byte("top secret"), 0644))
+	defer os.Remove(secret)
+
+	ctx := context.Background()
+	storage := NewLocalStorage(filepath.Join(dir, "sub"))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+
+	_, err := storage.Open(ctx, "../../secret.txt")
+	assert.ErrorIs(t, err, ErrInvalidName)
+
+	_, err = storage.Open(ctx, "/etc/passwd")
+	assert.ErrorIs(t, err, ErrInvalidName)
+}
+
+func TestLocalStorageStatAndRange(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	storage := NewLocalStorage(dir)
+
+	w, err := storage.Create(ctx, "data.bin")
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("0123456789"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	info, err := storage.Stat(ctx, "data.bin")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), info.Size)
+
+	rc, err := storage.Range(ctx, "data.bin", 3, 4)
+	assert.NoError(t, err)
+	defer rc.Close()
+	got, err := io.ReadAll(rc)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("3456"), got)
+}
@@ -12,9 +12,22 @@ var ErrInvalidCount = errors.New("invalid count")
 // Count return the total number of records in the reader
 // - all records are read/validated
 func Count(src io.Reader) (int64, error) {
+	return count(src, false)
+}
+
+// Verify walks every record in src, verifying its checksum if present, and returns
+// the total count; unlike Count, it always checks checksums, regardless of
+// SkipChecksumVerification
+func Verify(src io.Reader) (int64, error) {
+	return count(src, true)
+}
+
+// count walks every record in src, returning the total; if forceVerify is
+// true, each record's checksum is verified regardless of SkipChecksumVerification
+func count(src io.Reader, forceVerify bool) (int64, error) {
 	var total int64 = 0
 	for {
-		if _, err := Read(src); err != nil {
+		if _, err := readRecord(src, forceVerify); err != nil {
 			if errors.Is(err, io.EOF) {
 				break
 			} else {
@@ -28,6 +41,8 @@ func Count(src io.Reader) (int64, error) {
 
 // Extract reads count records from src from start offset; start offset starts at 0
 // if allowPrematureEnd is true, function can return partial results if EOF
+// if src implements io.ReadSeeker and carries a valid footer index, Extract seeks
+// directly to the start offset instead of rewinding through every preceding record
 func Extract(src io.Reader, start int64, count int64, allowPrematureEnd bool) ([]Record, error) {
 	if start < 0 {
 		return nil, ErrInvalidStartOffset
@@ -36,6 +51,21 @@ func Extract(src io.Reader, start int64, count int64, allowPrematureEnd bool) ([
 		return nil, ErrInvalidCount
 	}
 
+	if rs, ok := src.(io.ReadSeeker); ok {
+		cur, err := rs.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		if ir, err := OpenIndexed(rs); err == nil {
+			return ir.ReadRange(start, count, allowPrematureEnd)
+		}
+		// no valid footer index; restore the position OpenIndexed disturbed
+		// and fall back to reading from where the caller left off
+		if _, err := rs.Seek(cur, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
 	result := make([]Record, 0)
 	// first, attempt to skip
 	if start > 0 {
@@ -85,6 +115,8 @@ func Extract(src io.Reader, start int64, count int64, allowPrematureEnd bool) ([
 
 // Copy copies count records from src from start offset and writes them into dest; start offset starts at 0
 // if allowPrematureEnd is true, function can return partial results if EOF
+// if src implements io.ReadSeeker and carries a valid footer index, Copy seeks
+// directly to the start offset instead of rewinding through every preceding record
 func Copy(src io.Reader, dst io.Writer, start int64, count int64, compress bool, allowPrematureEnd bool) error {
 	if start < 0 {
 		return ErrInvalidStartOffset
@@ -93,6 +125,35 @@ func Copy(src io.Reader, dst io.Writer, start int64, count int64, compress bool,
 		return ErrInvalidCount
 	}
 
+	codec := None
+	if compress {
+		codec = Zstd
+	}
+
+	if rs, ok := src.(io.ReadSeeker); ok {
+		cur, err := rs.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		if ir, err := OpenIndexed(rs); err == nil {
+			rows, err := ir.ReadRange(start, count, allowPrematureEnd)
+			if err != nil {
+				return err
+			}
+			for _, r := range rows {
+				if err := WriteRecord(dst, r, codec); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		// no valid footer index; restore the position OpenIndexed disturbed
+		// and fall back to reading from where the caller left off
+		if _, err := rs.Seek(cur, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
 	// first, attempt to skip
 	if start > 1 {
 		for {
@@ -130,7 +191,7 @@ func Copy(src io.Reader, dst io.Writer, start int64, count int64, compress bool,
 				return err
 			}
 		} else {
-			if err = WriteRecord(dst, r, compress); err != nil {
+			if err = WriteRecord(dst, r, codec); err != nil {
 				return err
 			}
 		}
@@ -147,14 +208,17 @@ func skipRead(src io.Reader) error {
 		return err
 	}
 
-	// process compression & type
+	// process codec & type; the codec id itself is validated on decode, not on skip
 	var v uint32
 	if err := binary.Read(src, binary.LittleEndian, &v); err != nil {
 		return err
 	}
-	// check if reserved bits are zero
-	if ((v >> 16) & 0x7F) != 0 {
-		return ErrInvalidZeroBits
+
+	if v&dictFlagBit != 0 {
+		var dictID uint32
+		if err := binary.Read(src, binary.LittleEndian, &dictID); err != nil {
+			return err
+		}
 	}
 
 	var szMeta uint32
@@ -177,5 +241,11 @@ func skipRead(src io.Reader) error {
 			return err
 		}
 	}
+	if v&checksumFlagBit != 0 {
+		buf := make([]byte, 8)
+		if _, err := io.ReadFull(src, buf); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -8,12 +8,37 @@ import (
 	"testing"
 )
 
+// sampleRecord is a plain description of a record used to build test datasets,
+// independent of the wire-level record/Record types
+type sampleRecord struct {
+	RecordType uint16
+	Data       []byte
+	Meta       any
+}
+
+// testDataset returns a fixed set of TypeText sample records, alternating
+// with/without metadata, large enough to exercise multi-record
+// Extract/Copy windows
+func testDataset() []sampleRecord {
+	ds := make([]sampleRecord, 8)
+	for i := range ds {
+		ds[i] = sampleRecord{
+			RecordType: TypeText,
+			Data:       bytes.Repeat([]byte{byte('a' + i)}, 150),
+		}
+		if i%2 == 0 {
+			ds[i].Meta = map[string]int{"seq": i}
+		}
+	}
+	return ds
+}
+
 func testCount(t *testing.T, compress bool) {
 	buf := new(bytes.Buffer)
 	dataset := testDataset()
 
 	for _, r := range dataset {
-		err := Write(buf, r.RecordType, r.Data, r.Meta, false)
+		err := Write(buf, r.RecordType, r.Data, r.Meta, None)
 		assert.NoError(t, err)
 	}
 
@@ -60,7 +85,7 @@ func bufDataset(t *testing.T, ds []sampleRecord) *bytes.Buffer {
 	buf := new(bytes.Buffer)
 
 	for _, r := range ds {
-		err := Write(buf, r.RecordType, r.Data, r.Meta, false)
+		err := Write(buf, r.RecordType, r.Data, r.Meta, None)
 		assert.NoError(t, err)
 	}
 	return buf
@@ -95,7 +120,7 @@ func testExtractSimple(t *testing.T, compress bool) {
 	assert.Equal(t, 3, len(rows))
 	for i := 0; i < 3; i++ {
 		assert.Equal(t, ds[4+i].Data, rows[i].Bytes())
-		if ds[3+i].Meta != nil {
+		if ds[4+i].Meta != nil {
 			meta, err := json.Marshal(ds[4+i].Meta)
 			assert.NoError(t, err)
 			assert.Equal(t, meta, rows[i].Meta())
@@ -127,6 +152,25 @@ func testExtractEnd(t *testing.T, compress bool) {
 	}
 }
 
+// TestExtractFromCurrentPosition covers a reader that has already been
+// partially consumed before Extract is called: without a footer index,
+// start must be interpreted relative to the reader's current position, not
+// rewound to the start of the stream
+func TestExtractFromCurrentPosition(t *testing.T) {
+	ds := testDataset()
+	buf := bufDataset(t, ds)
+	src := bytes.NewReader(buf.Bytes())
+
+	// advance past record 0
+	_, err := Read(src)
+	assert.NoError(t, err)
+
+	rows, err := Extract(src, 0, 1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, ds[1].Data, rows[0].Bytes())
+}
+
 func TestExtract(t *testing.T) {
 	// empty buffer
 	testExtractEmpty(t, false)
@@ -0,0 +1,75 @@
+package go_srf
+
+import (
+	"github.com/klauspost/compress/zstd"
+	"sync"
+)
+
+// encoderOpts and decoderOpts configure every *zstd.Encoder/*zstd.Decoder the
+// pools construct; change them with SetEncoderOptions/SetDecoderOptions before
+// the pools are warmed up, since already-pooled instances keep their options
+var (
+	encoderOptsMu sync.RWMutex
+	encoderOpts   = []zstd.EOption{zstd.WithEncoderConcurrency(EncoderConcurrency), zstd.WithEncoderCRC(true)}
+
+	decoderOptsMu sync.RWMutex
+	decoderOpts   = []zstd.DOption{zstd.WithDecoderConcurrency(DecoderConcurrency)}
+)
+
+// SetEncoderOptions overrides the options used to construct pooled zstd encoders,
+// e.g. to tune compression level or window size; it only affects encoders
+// created after the call, not ones already sitting in the pool
+func SetEncoderOptions(opts ...zstd.EOption) {
+	encoderOptsMu.Lock()
+	encoderOpts = opts
+	encoderOptsMu.Unlock()
+}
+
+// SetDecoderOptions overrides the options used to construct pooled zstd decoders
+func SetDecoderOptions(opts ...zstd.DOption) {
+	decoderOptsMu.Lock()
+	decoderOpts = opts
+	decoderOptsMu.Unlock()
+}
+
+var zstdEncoderPool = sync.Pool{
+	New: func() any {
+		encoderOptsMu.RLock()
+		opts := encoderOpts
+		encoderOptsMu.RUnlock()
+		enc, _ := zstd.NewWriter(nil, opts...)
+		return enc
+	},
+}
+
+var zstdDecoderPool = sync.Pool{
+	New: func() any {
+		decoderOptsMu.RLock()
+		opts := decoderOpts
+		decoderOptsMu.RUnlock()
+		dec, _ := zstd.NewReader(nil, opts...)
+		return dec
+	},
+}
+
+// getZstdEncoder returns a pooled *zstd.Encoder; callers must return it with putZstdEncoder
+func getZstdEncoder() *zstd.Encoder {
+	return zstdEncoderPool.Get().(*zstd.Encoder)
+}
+
+// putZstdEncoder resets enc and returns it to the pool
+func putZstdEncoder(enc *zstd.Encoder) {
+	enc.Reset(nil)
+	zstdEncoderPool.Put(enc)
+}
+
+// getZstdDecoder returns a pooled *zstd.Decoder; callers must return it with putZstdDecoder
+func getZstdDecoder() *zstd.Decoder {
+	return zstdDecoderPool.Get().(*zstd.Decoder)
+}
+
+// putZstdDecoder resets dec and returns it to the pool
+func putZstdDecoder(dec *zstd.Decoder) {
+	_ = dec.Reset(nil)
+	zstdDecoderPool.Put(dec)
+}